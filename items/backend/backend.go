@@ -0,0 +1,65 @@
+// Package backend selects and constructs the ItemsResourcePort implementation
+// the server runs against. It's the one place that knows about every backend
+// package (json, sqlite, redis, upstream); everything else — the controller,
+// main.go — only ever sees a ports.ItemsResourcePort.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"items/ports"
+	"items/resources/items"
+	"items/resources/redisresource"
+	"items/resources/sqlite"
+	"items/resources/upstream"
+)
+
+const (
+	defaultItemsJSONPath      = "./data/items.json"
+	defaultCategoriesJSONPath = "./data/categories.json"
+)
+
+// New builds the ItemsResourcePort selected by the ITEMS_BACKEND env var
+// (json, sqlite, redis or upstream, defaulting to json), reading whatever
+// backend-specific env vars that choice needs.
+func New(ctx context.Context) (ports.ItemsResourcePort, error) {
+	itemsPath := envOrDefault("ITEMS_JSON_PATH", defaultItemsJSONPath)
+	categoriesPath := envOrDefault("CATEGORIES_JSON_PATH", defaultCategoriesJSONPath)
+
+	switch name := os.Getenv("ITEMS_BACKEND"); name {
+	case "", "json":
+		return items.NewJSONResource(itemsPath, categoriesPath), nil
+
+	case "sqlite":
+		return sqlite.New(ctx, sqlite.Config{
+			DBPath:           envOrDefault("SQLITE_DB_PATH", "./data/items.db"),
+			ItemJSONPath:     itemsPath,
+			CategoryJSONPath: categoriesPath,
+		})
+
+	case "redis":
+		return redisresource.New(ctx, redisresource.Config{
+			Addr:             envOrDefault("REDIS_ADDR", "localhost:6379"),
+			ItemJSONPath:     itemsPath,
+			CategoryJSONPath: categoriesPath,
+		})
+
+	case "upstream":
+		return upstream.New(upstream.Config{
+			BaseURL: os.Getenv("UPSTREAM_BASE_URL"),
+			SiteID:  os.Getenv("UPSTREAM_SITE_ID"),
+		})
+
+	default:
+		return nil, fmt.Errorf("backend: unknown ITEMS_BACKEND %q", name)
+	}
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
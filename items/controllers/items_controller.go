@@ -1,14 +1,74 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"items/ports"
 	res "items/resources/items" // alias sólo para abreviar
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// adminSecretHeader carries the shared secret required to trigger a manual
+// reload. The expected value is read from ADMIN_RELOAD_SECRET; the endpoint
+// refuses every request when that env var is unset.
+const adminSecretHeader = "X-Admin-Secret"
+
+// statusClientClosedRequest mirrors nginx's convention for a client that
+// disconnected before the response was ready; net/http has no constant for it.
+const statusClientClosedRequest = 499
+
+// defaultMaxIDsPerRequest caps how many IDs a single `?ids=` batch may
+// contain, overridable with MAX_IDS_PER_REQUEST.
+const defaultMaxIDsPerRequest = 5000
+
+// defaultMaxRecvBytes bounds request body size, overridable with
+// MAX_RECV_BYTES. All current endpoints are GET-only, so this only bites
+// on future POST variants, but it's wired into the server's body-limit
+// middleware (see main.go) so it isn't dead weight until then.
+const defaultMaxRecvBytes = 1 << 20 // 1MiB
+
+// ndjsonContentType is the media type that opts a batch request into
+// streamed, line-delimited JSON instead of a single buffered array.
+const ndjsonContentType = "application/x-ndjson"
+
+// maxIDsPerRequest returns the configured cap on IDs per `?ids=` batch.
+func maxIDsPerRequest() int {
+	if v := os.Getenv("MAX_IDS_PER_REQUEST"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultMaxIDsPerRequest
+}
+
+// MaxRecvBytes returns the configured request body size limit, for main.go
+// to wire into the server's body-limit middleware.
+func MaxRecvBytes() int {
+	if v := os.Getenv("MAX_RECV_BYTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultMaxRecvBytes
+}
+
+// wantsStream reports whether the caller asked for NDJSON streaming, either
+// via `Accept: application/x-ndjson` or `?stream=1`.
+func wantsStream(ctx echo.Context) bool {
+	if ctx.QueryParam("stream") == "1" {
+		return true
+	}
+	return strings.Contains(ctx.Request().Header.Get(echo.HeaderAccept), ndjsonContentType)
+}
+
 // ItemController maneja rutas HTTP relacionadas con ítems y ahora también categorías.
 type ItemController struct {
 	resource ports.ItemsResourcePort
@@ -33,14 +93,26 @@ var errorResponseMap = map[error]struct {
 		Code:    http.StatusNotFound,
 		Message: "No categories found for the provided IDs",
 	},
+	context.DeadlineExceeded: {
+		Code:    http.StatusGatewayTimeout,
+		Message: "The request exceeded its deadline",
+	},
+	context.Canceled: {
+		Code:    statusClientClosedRequest,
+		Message: "The client disconnected before the request completed",
+	},
 }
 
-// writeError envía una respuesta JSON estandarizada para errores.
+// writeError envía una respuesta JSON estandarizada para errores. Backends
+// wrap their failures with fmt.Errorf("...: %w", err), so matching must
+// unwrap via errors.Is instead of comparing errors by identity.
 func writeError(ctx echo.Context, err error) error {
-	if resp, exists := errorResponseMap[err]; exists {
-		return ctx.JSON(resp.Code, map[string]string{
-			"error": resp.Message,
-		})
+	for candidate, resp := range errorResponseMap {
+		if errors.Is(err, candidate) {
+			return ctx.JSON(resp.Code, map[string]string{
+				"error": resp.Message,
+			})
+		}
 	}
 
 	return ctx.JSON(http.StatusInternalServerError, map[string]string{
@@ -48,6 +120,217 @@ func writeError(ctx echo.Context, err error) error {
 	})
 }
 
+// requestContext builds the context used for a resource call: the Echo
+// request's context, plus an optional per-call deadline derived from the
+// `?timeout=` query parameter (e.g. `timeout=500ms`). The returned cancel
+// func must be deferred by the caller — it's a no-op when no timeout was
+// requested, and releases the timer otherwise.
+func (c *ItemController) requestContext(ctx echo.Context) (context.Context, context.CancelFunc, error) {
+	reqCtx := ctx.Request().Context()
+
+	timeoutParam := ctx.QueryParam("timeout")
+	if timeoutParam == "" {
+		return reqCtx, func() {}, nil
+	}
+
+	timeout, err := time.ParseDuration(timeoutParam)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deadlineCtx, cancel := context.WithTimeout(reqCtx, timeout)
+	return deadlineCtx, cancel, nil
+}
+
+// envelope wraps every /v1 response body so clients can distinguish payload
+// from pagination metadata without guessing the shape of `data`.
+type envelope struct {
+	Data   interface{} `json:"data"`
+	Paging *paging     `json:"paging,omitempty"`
+}
+
+type paging struct {
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	Total      int  `json:"total"`
+	NextOffset *int `json:"next_offset,omitempty"`
+}
+
+// buildPaging summarizes a page of `returned` items out of `total`,
+// including the offset of the next page when there's more to fetch.
+func buildPaging(page res.Page, total, returned int) *paging {
+	p := &paging{Limit: page.Limit, Offset: page.Offset, Total: total}
+	if next := page.Offset + returned; next < total {
+		p.NextOffset = &next
+	}
+	return p
+}
+
+// parsePage reads `limit`/`offset` query parameters into a res.Page.
+func parsePage(ctx echo.Context) (res.Page, error) {
+	var page res.Page
+
+	if v := ctx.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return res.Page{}, fmt.Errorf("invalid 'limit' query parameter")
+		}
+		page.Limit = n
+	}
+
+	if v := ctx.QueryParam("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return res.Page{}, fmt.Errorf("invalid 'offset' query parameter")
+		}
+		page.Offset = n
+	}
+
+	return page, nil
+}
+
+// GetItemHandler maneja GET /v1/items/:id
+func (c *ItemController) GetItemHandler(ctx echo.Context) error {
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
+	itm, err := c.resource.GetItem(reqCtx, ctx.Param("id"))
+	if err != nil {
+		return writeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, envelope{Data: itm})
+}
+
+// ListItemsHandler maneja GET /v1/items?limit=&offset=&seller_id=&category_id=
+func (c *ItemController) ListItemsHandler(ctx echo.Context) error {
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
+	page, err := parsePage(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	filter := res.ListFilter{
+		SellerID:   ctx.QueryParam("seller_id"),
+		CategoryID: ctx.QueryParam("category_id"),
+	}
+
+	result, total, err := c.resource.ListItems(reqCtx, filter, page)
+	if err != nil {
+		return writeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, envelope{
+		Data:   result,
+		Paging: buildPaging(page, total, len(result)),
+	})
+}
+
+// GetCategoryHandler maneja GET /v1/categories/:id — returns the category
+// with its direct children resolved (name, path, etc.), not just the bare
+// {id} refs ChildrenCategories carries. Unlike GetCategoryTreeHandler, it
+// doesn't recurse past that first level.
+func (c *ItemController) GetCategoryHandler(ctx echo.Context) error {
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
+	cats, err := c.resource.GetCategoriesByIDs(reqCtx, []string{ctx.Param("id")})
+	if err != nil {
+		return writeError(ctx, err)
+	}
+	cat := cats[0]
+
+	node := res.CategoryNode{Category: cat}
+	if len(cat.ChildrenCategories) > 0 {
+		childIDs := make([]string, len(cat.ChildrenCategories))
+		for i, ref := range cat.ChildrenCategories {
+			childIDs[i] = ref.ID
+		}
+
+		children, err := c.resource.GetCategoriesByIDs(reqCtx, childIDs)
+		if err != nil && !errors.Is(err, res.ErrCategoryNotFound) {
+			return writeError(ctx, err)
+		}
+		for _, child := range children {
+			node.Children = append(node.Children, res.CategoryNode{Category: child})
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, envelope{Data: node})
+}
+
+// GetCategoryItemsHandler maneja GET /v1/categories/:id/items
+func (c *ItemController) GetCategoryItemsHandler(ctx echo.Context) error {
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
+	result, err := c.resource.GetItemsByCategory(reqCtx, ctx.Param("id"))
+	if err != nil {
+		return writeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, envelope{Data: result})
+}
+
+// GetCategoryTreeHandler maneja GET /v1/categories/:id/tree
+func (c *ItemController) GetCategoryTreeHandler(ctx echo.Context) error {
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
+	tree, err := c.resource.GetCategoryTree(reqCtx, ctx.Param("id"))
+	if err != nil {
+		return writeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, envelope{Data: tree})
+}
+
+// ReloadHandler maneja POST /admin/reload, guardado por un secreto compartido
+// en el header X-Admin-Secret.
+func (c *ItemController) ReloadHandler(ctx echo.Context) error {
+	secret := os.Getenv("ADMIN_RELOAD_SECRET")
+	if secret == "" || ctx.Request().Header.Get(adminSecretHeader) != secret {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Invalid or missing admin secret",
+		})
+	}
+
+	if err := c.resource.Reload(); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to reload data files",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{"status": "reloaded"})
+}
+
 // GetItemsHandler maneja GET /items?ids=MLA1,MLA2
 func (c *ItemController) GetItemsHandler(ctx echo.Context) error {
 	idsParam := ctx.QueryParam("ids")
@@ -57,9 +340,26 @@ func (c *ItemController) GetItemsHandler(ctx echo.Context) error {
 		})
 	}
 
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
 	ids := strings.Split(idsParam, ",")
+	if len(ids) > maxIDsPerRequest() {
+		return ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("Too many IDs: got %d, max is %d", len(ids), maxIDsPerRequest()),
+		})
+	}
+
+	if wantsStream(ctx) {
+		return c.streamItems(ctx, reqCtx, ids)
+	}
 
-	result, err := c.resource.GetItemsByIDs(ids)
+	result, err := c.resource.GetItemsByIDs(reqCtx, ids)
 	if err != nil {
 		return writeError(ctx, err)
 	}
@@ -67,6 +367,26 @@ func (c *ItemController) GetItemsHandler(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, result)
 }
 
+// streamItems writes matching items as NDJSON, one object per line, flushing
+// after each record instead of buffering the whole batch in memory. The
+// flush after each record is what makes net/http switch the response to
+// chunked transfer encoding on its own — setting the header by hand isn't
+// necessary and net/http ignores it anyway.
+func (c *ItemController) streamItems(ctx echo.Context, reqCtx context.Context, ids []string) error {
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, ndjsonContentType)
+	resp.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(resp)
+	return c.resource.StreamItemsByIDs(reqCtx, ids, func(itm res.Item) error {
+		if err := enc.Encode(itm); err != nil {
+			return err
+		}
+		resp.Flush()
+		return nil
+	})
+}
+
 // GetCategoriesHandler maneja GET /categories?ids=MLA100,MLA200
 func (c *ItemController) GetCategoriesHandler(ctx echo.Context) error {
 	idsParam := ctx.QueryParam("ids")
@@ -76,12 +396,48 @@ func (c *ItemController) GetCategoriesHandler(ctx echo.Context) error {
 		})
 	}
 
+	reqCtx, cancel, err := c.requestContext(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid 'timeout' query parameter",
+		})
+	}
+	defer cancel()
+
 	ids := strings.Split(idsParam, ",")
+	if len(ids) > maxIDsPerRequest() {
+		return ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("Too many IDs: got %d, max is %d", len(ids), maxIDsPerRequest()),
+		})
+	}
+
+	if wantsStream(ctx) {
+		return c.streamCategoryGroups(ctx, reqCtx, ids)
+	}
 
-	result, err := c.resource.GroupItemIDsByRootCategory(ids)
+	result, err := c.resource.GroupItemIDsByRootCategory(reqCtx, ids)
 	if err != nil {
 		return writeError(ctx, err)
 	}
 
 	return ctx.JSON(http.StatusOK, result)
 }
+
+// streamCategoryGroups writes root-category groups as NDJSON, one object per
+// line, flushing after each record. net/http switches the response to
+// chunked transfer encoding on its own once we flush; it manages that
+// header itself, so setting it here would be dead at best.
+func (c *ItemController) streamCategoryGroups(ctx echo.Context, reqCtx context.Context, ids []string) error {
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, ndjsonContentType)
+	resp.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(resp)
+	return c.resource.StreamCategoryGroups(reqCtx, ids, func(group res.CategoryGroup) error {
+		if err := enc.Encode(group); err != nil {
+			return err
+		}
+		resp.Flush()
+		return nil
+	})
+}
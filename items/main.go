@@ -1,35 +1,50 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"items/backend"
 	controller "items/controllers"
-	"items/resources/items"
 )
 
 const defaultPort = ":8080"
-const itemsPath = "./data/items.json"
-const categoriesPath = "./data/categories.json"
 
 func main() {
 	// Initialize Echo
 	e := echo.New()
+	e.Use(middleware.BodyLimit(strconv.Itoa(controller.MaxRecvBytes())))
 
-	// Load item resource
-	itemsResource := items.NewResource(itemsPath, categoriesPath)
-	log.Printf("✅ Item resource loaded from %s", itemsPath)
+	// Load the configured item resource backend (ITEMS_BACKEND: json,
+	// sqlite, redis or upstream — defaults to json).
+	itemsResource, err := backend.New(context.Background())
+	if err != nil {
+		log.Fatalf("❌ failed to initialize items backend: %v", err)
+	}
+	log.Printf("✅ Item resource ready")
 
 	// Initialize controller with the resource
 	itemController := controller.NewItemController(itemsResource)
 
-	// Register routes
-	e.GET("/items", itemController.GetItemsHandler)
+	// v1: versioned, per-resource endpoints with pagination.
+	v1 := e.Group("/v1")
+	v1.GET("/items/:id", itemController.GetItemHandler)
+	v1.GET("/items", itemController.ListItemsHandler)
+	v1.GET("/categories/:id", itemController.GetCategoryHandler)
+	v1.GET("/categories/:id/items", itemController.GetCategoryItemsHandler)
+	v1.GET("/categories/:id/tree", itemController.GetCategoryTreeHandler)
 
-	// Register routes
+	// Back-compat: batch-by-query-string endpoints predating /v1.
+	e.GET("/items", itemController.GetItemsHandler)
 	e.GET("/categories", itemController.GetCategoriesHandler)
 
+	// Manual hot-reload trigger, for operators after deploying new data files.
+	e.POST("/admin/reload", itemController.ReloadHandler)
+
 	// Start server
 	port := getPort()
 	log.Printf("🚀 Starting server on %s", port)
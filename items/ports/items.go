@@ -1,11 +1,37 @@
 package ports
 
 import (
+	"context"
+
 	"items/resources/items"
 )
 
 type ItemsResourcePort interface {
-	GetItemsByIDs(ids []string) ([]items.Item, error)
-	GetCategoriesByIDs(ids []string) ([]items.Category, error)
-	GroupItemIDsByRootCategory(ids []string) ([]items.CategoryGroup, error)
+	GetItemsByIDs(ctx context.Context, ids []string) ([]items.Item, error)
+	GetCategoriesByIDs(ctx context.Context, ids []string) ([]items.Category, error)
+	GroupItemIDsByRootCategory(ctx context.Context, ids []string) ([]items.CategoryGroup, error)
+
+	// StreamItemsByIDs resolves ids one at a time and invokes emit for each
+	// match, without materializing a []Item, so large batches can be
+	// streamed straight to the response.
+	StreamItemsByIDs(ctx context.Context, ids []string, emit func(items.Item) error) error
+	// StreamCategoryGroups groups ids by root category, invoking emit as
+	// soon as each group is computed.
+	StreamCategoryGroups(ctx context.Context, ids []string, emit func(items.CategoryGroup) error) error
+
+	// GetItem returns a single item by ID, or ErrItemNotFound on a miss.
+	GetItem(ctx context.Context, id string) (items.Item, error)
+	// ListItems returns a page of items matching filter plus the total
+	// number of matches (before pagination) so callers can compute paging.
+	ListItems(ctx context.Context, filter items.ListFilter, page items.Page) ([]items.Item, int, error)
+	// GetItemsByCategory returns every item under a root or leaf category.
+	GetItemsByCategory(ctx context.Context, categoryID string) ([]items.Item, error)
+	// GetCategoryTree returns a category with its children resolved
+	// recursively.
+	GetCategoryTree(ctx context.Context, id string) (items.CategoryNode, error)
+
+	// Reload rebuilds the resource's data from its backing store. The json
+	// backend also triggers it automatically on file changes; every backend
+	// can have it invoked manually (e.g. via POST /admin/reload).
+	Reload() error
 }
@@ -0,0 +1,31 @@
+// Package deadline provides the cancellation-checking helper shared by every
+// ItemsResourcePort backend (JSON, SQLite, Redis, upstream), so each one
+// doesn't have to reimplement its own periodic ctx.Done() check.
+//
+// Deadlines themselves are ordinary context.Context values: a caller that
+// wants to bound a call derives one with context.WithTimeout before invoking
+// a backend (the HTTP layer does this for the `?timeout=` query parameter).
+// This package only covers checking that context cheaply inside tight loops.
+package deadline
+
+import "context"
+
+// CheckEvery controls how many loop iterations elapse between cancellation
+// checks in backends that iterate in Go (e.g. over an ID batch). Checking on
+// every iteration would add a select to every map/row lookup; checking too
+// rarely would let a cancelled request keep scanning for too long.
+const CheckEvery = 256
+
+// CheckCancelled checks, every CheckEvery iterations, whether ctx has been
+// cancelled or its deadline has expired.
+func CheckCancelled(ctx context.Context, i int) error {
+	if i%CheckEvery != 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
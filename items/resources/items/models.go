@@ -25,3 +25,23 @@ type CategoryGroup struct {
 	RootCategoryID string   `json:"root_category_id"`
 	ItemIDs        []string `json:"item_ids"`
 }
+
+// ListFilter narrows down ListItems to items belonging to a given seller
+// and/or category. A zero-value ListFilter matches every item.
+type ListFilter struct {
+	SellerID   string
+	CategoryID string
+}
+
+// Page requests a window of a result set using limit/offset pagination.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// CategoryNode is a Category together with its fully resolved children,
+// used to build the `/v1/categories/:id/tree` response.
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children,omitempty"`
+}
@@ -1,50 +1,202 @@
 package items
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"items/resources/deadline"
+	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
 )
 
-// Resource provides in‑memory access and indexes for items and categories.
-// Besides the raw maps loaded from JSON, it maintains an index for fast
-// resolution item_id → root_category_id.
-type Resource struct {
+// defaultListLimit is the page size ListItems uses when the caller doesn't
+// request one explicitly.
+const defaultListLimit = 50
+
+// snapshot bundles the maps and indexes built from a single load of the
+// items/categories JSON files. JSONResource swaps in a new snapshot wholesale on
+// reload so readers never observe a half-rebuilt index.
+type snapshot struct {
 	items              map[string]Item
 	categories         map[string]Category
-	itemToRootCategory map[string]string // itemID → rootCategoryID
+	itemToRootCategory map[string]string   // itemID → rootCategoryID
+	itemsBySeller      map[string][]string // sellerID → itemIDs
+	itemsByCategory    map[string][]string // leaf categoryID → itemIDs
+	itemsByRoot        map[string][]string // root categoryID → itemIDs
+}
+
+// JSONResource provides in‑memory access and indexes for items and categories.
+// It watches the JSON files it was loaded from and hot-reloads its snapshot
+// on write/rename, so all read methods take an RLock to protect against a
+// reload swapping the snapshot mid-read.
+type JSONResource struct {
+	mu               sync.RWMutex
+	snap             *snapshot
+	itemJSONPath     string
+	categoryJSONPath string
+}
+
+// NewJSONResource loads items & categories from disk, builds the item→rootCategory
+// index plus the secondary indexes used by ListItems and GetItemsByCategory,
+// and starts a background watcher that hot-reloads the snapshot whenever the
+// files change. It panics if the initial load fails, since there is no prior
+// snapshot to fall back to.
+func NewJSONResource(itemJSONPath, categoryJSONPath string) *JSONResource {
+	snap, err := loadSnapshot(itemJSONPath, categoryJSONPath)
+	if err != nil {
+		panic(err)
+	}
+
+	r := &JSONResource{
+		snap:             snap,
+		itemJSONPath:     itemJSONPath,
+		categoryJSONPath: categoryJSONPath,
+	}
+
+	go r.watch()
+
+	return r
+}
+
+// Reload rebuilds the snapshot from disk and swaps it in atomically. On
+// malformed input it logs the error, keeps serving the previously-loaded
+// snapshot, and returns the error to the caller.
+func (r *JSONResource) Reload() error {
+	snap, err := loadSnapshot(r.itemJSONPath, r.categoryJSONPath)
+	if err != nil {
+		log.Printf("items: reload failed, keeping previous snapshot: %v", err)
+		return err
+	}
+
+	r.mu.Lock()
+	r.snap = snap
+	r.mu.Unlock()
+
+	log.Printf("items: reloaded %d items and %d categories", len(snap.items), len(snap.categories))
+	return nil
 }
 
-// NewResource loads items & categories from disk and builds the
-// item→rootCategory index so look‑ups are O(1).
-func NewResource(itemJSONPath, categoryJSONPath string) *Resource {
-	items := loadItems(itemJSONPath)
-	categories := loadCategories(categoryJSONPath)
+// watch reloads the snapshot whenever itemJSONPath or categoryJSONPath
+// change on disk. It watches the containing directories rather than the
+// file paths themselves: a write-temp-then-rename save (what most editors
+// and deployment scripts do) replaces the inode fsnotify is watching, so a
+// watch on the file path alone stops delivering events after the first
+// atomic swap. Watching the directory and filtering by filename survives
+// that. It runs for the lifetime of the process; reload failures are logged
+// by Reload and don't stop the watcher.
+func (r *JSONResource) watch() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("items: fsnotify unavailable, hot-reload disabled: %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	targets := map[string]bool{
+		filepath.Clean(r.itemJSONPath):     true,
+		filepath.Clean(r.categoryJSONPath): true,
+	}
+
+	dirs := make(map[string]bool)
+	for path := range targets {
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			log.Printf("items: failed to watch %s: %v", dir, err)
+		}
+	}
 
-	return &Resource{
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !targets[filepath.Clean(event.Name)] {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			_ = r.Reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("items: fsnotify error: %v", err)
+		}
+	}
+}
+
+// checkCancelled checks, every deadline.CheckEvery iterations, whether ctx
+// was cancelled or its deadline — set via context.WithTimeout by the HTTP
+// layer's `?timeout=` query parameter — expired.
+func (r *JSONResource) checkCancelled(ctx context.Context, i int) error {
+	return deadline.CheckCancelled(ctx, i)
+}
+
+// loadSnapshot reads the items & categories JSON files and builds a fresh
+// snapshot, or returns an error without touching any shared state.
+func loadSnapshot(itemJSONPath, categoryJSONPath string) (*snapshot, error) {
+	items, err := loadItems(itemJSONPath)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := loadCategories(categoryJSONPath)
+	if err != nil {
+		return nil, err
+	}
+
+	itemToRootCategory := buildItemToRootCategoryIndex(items, categories)
+	itemsBySeller, itemsByCategory, itemsByRoot := buildSecondaryIndexes(items, itemToRootCategory)
+
+	return &snapshot{
 		items:              items,
 		categories:         categories,
-		itemToRootCategory: buildItemToRootCategoryIndex(items, categories),
-	}
+		itemToRootCategory: itemToRootCategory,
+		itemsBySeller:      itemsBySeller,
+		itemsByCategory:    itemsByCategory,
+		itemsByRoot:        itemsByRoot,
+	}, nil
+}
+
+// LoadItemsFile reads an items JSON file into a map keyed by item ID. It's
+// exported so other ItemsResourcePort backends (SQLite, Redis) can import
+// the same JSON dataset into their own store instead of serving it directly
+// from memory.
+func LoadItemsFile(path string) (map[string]Item, error) {
+	return loadItems(path)
+}
+
+// LoadCategoriesFile reads a categories JSON file the same way LoadItemsFile
+// does for items.
+func LoadCategoriesFile(path string) (map[string]Category, error) {
+	return loadCategories(path)
 }
 
 // loadItems reads an items JSON file and unmarshals it into a map.
-func loadItems(path string) map[string]Item {
+func loadItems(path string) (map[string]Item, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		panic(fmt.Sprintf("failed to open items JSON file: %v", err))
+		return nil, fmt.Errorf("failed to open items JSON file: %w", err)
 	}
 	defer file.Close()
 
 	bytes, err := io.ReadAll(file)
 	if err != nil {
-		panic(fmt.Sprintf("failed to read items JSON file: %v", err))
+		return nil, fmt.Errorf("failed to read items JSON file: %w", err)
 	}
 
 	var data map[string]Item
 	if err := json.Unmarshal(bytes, &data); err != nil {
-		panic(fmt.Sprintf("failed to unmarshal items JSON: %v", err))
+		return nil, fmt.Errorf("failed to unmarshal items JSON: %w", err)
 	}
 
 	// Copy the map key into the struct so downstream code has the ID field.
@@ -53,25 +205,25 @@ func loadItems(path string) map[string]Item {
 		data[id] = itm
 	}
 
-	return data
+	return data, nil
 }
 
 // loadCategories reads a categories JSON file and unmarshals it into a map.
-func loadCategories(path string) map[string]Category {
+func loadCategories(path string) (map[string]Category, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		panic(fmt.Sprintf("failed to open categories JSON file: %v", err))
+		return nil, fmt.Errorf("failed to open categories JSON file: %w", err)
 	}
 	defer file.Close()
 
 	bytes, err := io.ReadAll(file)
 	if err != nil {
-		panic(fmt.Sprintf("failed to read categories JSON file: %v", err))
+		return nil, fmt.Errorf("failed to read categories JSON file: %w", err)
 	}
 
 	var data map[string]Category
 	if err := json.Unmarshal(bytes, &data); err != nil {
-		panic(fmt.Sprintf("failed to unmarshal categories JSON: %v", err))
+		return nil, fmt.Errorf("failed to unmarshal categories JSON: %w", err)
 	}
 
 	for id, cat := range data {
@@ -79,7 +231,7 @@ func loadCategories(path string) map[string]Category {
 		data[id] = cat
 	}
 
-	return data
+	return data, nil
 }
 
 // buildItemToRootCategoryIndex precomputes itemID → rootCategoryID so future
@@ -104,12 +256,47 @@ func buildItemToRootCategoryIndex(items map[string]Item, categories map[string]C
 	return idx
 }
 
+// buildSecondaryIndexes precomputes sellerID → itemIDs, leaf categoryID →
+// itemIDs and root categoryID → itemIDs so ListItems and GetItemsByCategory
+// don't need to scan the full items map on every call. IDs within each
+// bucket are sorted for stable pagination across calls.
+func buildSecondaryIndexes(items map[string]Item, itemToRootCategory map[string]string) (bySeller, byCategory, byRoot map[string][]string) {
+	bySeller = make(map[string][]string)
+	byCategory = make(map[string][]string)
+	byRoot = make(map[string][]string)
+
+	for id, itm := range items {
+		bySeller[itm.SellerID] = append(bySeller[itm.SellerID], id)
+		byCategory[itm.CategoryID] = append(byCategory[itm.CategoryID], id)
+		if root, ok := itemToRootCategory[id]; ok {
+			byRoot[root] = append(byRoot[root], id)
+		}
+	}
+
+	for _, idx := range []map[string][]string{bySeller, byCategory, byRoot} {
+		for _, ids := range idx {
+			sort.Strings(ids)
+		}
+	}
+
+	return bySeller, byCategory, byRoot
+}
+
 // GetItemsByIDs returns the items matching the provided IDs.
-// If none are found, ErrItemNotFound is returned.
-func (r *Resource) GetItemsByIDs(ids []string) ([]Item, error) {
+// If none are found, ErrItemNotFound is returned. Large ID batches are
+// checked against ctx every deadline.CheckEvery iterations so a disconnected client or an expired `?timeout=` stops the
+// scan early instead of running to completion.
+func (r *JSONResource) GetItemsByIDs(ctx context.Context, ids []string) ([]Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := r.snap
+
 	result := make([]Item, 0, len(ids))
-	for _, id := range ids {
-		if itm, ok := r.items[id]; ok {
+	for i, id := range ids {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+		if itm, ok := snap.items[id]; ok {
 			result = append(result, itm)
 		}
 	}
@@ -119,12 +306,50 @@ func (r *Resource) GetItemsByIDs(ids []string) ([]Item, error) {
 	return result, nil
 }
 
+// StreamItemsByIDs resolves ids one at a time and invokes emit for each
+// match, without ever materializing a []Item — the caller's emit is
+// expected to write the item straight to its destination (e.g. an HTTP
+// response body). It stops and returns the first error from ctx
+// (cancellation or an expired deadline) or from emit itself. Unlike
+// GetItemsByIDs, a batch with zero matches is not an error: it simply
+// emits nothing.
+func (r *JSONResource) StreamItemsByIDs(ctx context.Context, ids []string, emit func(Item) error) error {
+	// Snapshots are immutable and swapped wholesale on reload, so it's safe
+	// to grab the pointer under a brief lock and release it before emit runs
+	// — emit does I/O (e.g. writing to an HTTP response), and holding RLock
+	// across that would let a slow client block a concurrent Reload.
+	r.mu.RLock()
+	snap := r.snap
+	r.mu.RUnlock()
+
+	for i, id := range ids {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return err
+		}
+		itm, ok := snap.items[id]
+		if !ok {
+			continue
+		}
+		if err := emit(itm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetCategoriesByIDs returns the categories matching the provided IDs.
 // If none are found, ErrCategoryNotFound is returned.
-func (r *Resource) GetCategoriesByIDs(ids []string) ([]Category, error) {
+func (r *JSONResource) GetCategoriesByIDs(ctx context.Context, ids []string) ([]Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := r.snap
+
 	result := make([]Category, 0, len(ids))
-	for _, id := range ids {
-		if cat, ok := r.categories[id]; ok {
+	for i, id := range ids {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+		if cat, ok := snap.categories[id]; ok {
 			result = append(result, cat)
 		}
 	}
@@ -141,16 +366,23 @@ func (r *Resource) GetCategoriesByIDs(ids []string) ([]Category, error) {
 //
 // It silently ignores unknown item IDs but returns ErrItemNotFound if *all*
 // requested IDs are unknown. The caller can choose how to handle partial misses.
-func (r *Resource) GroupItemIDsByRootCategory(ids []string) ([]CategoryGroup, error) {
+func (r *JSONResource) GroupItemIDsByRootCategory(ctx context.Context, ids []string) ([]CategoryGroup, error) {
 	if len(ids) == 0 {
 		return nil, ErrItemNotFound
 	}
 
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := r.snap
+
 	// rootCatID → []itemID
 	tmp := make(map[string][]string)
 
-	for _, id := range ids {
-		if root, ok := r.itemToRootCategory[id]; ok {
+	for i, id := range ids {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+		if root, ok := snap.itemToRootCategory[id]; ok {
 			tmp[root] = append(tmp[root], id)
 		}
 	}
@@ -169,3 +401,193 @@ func (r *Resource) GroupItemIDsByRootCategory(ids []string) ([]CategoryGroup, er
 
 	return groups, nil
 }
+
+// StreamCategoryGroups groups ids by root category, like
+// GroupItemIDsByRootCategory, but invokes emit for each group as soon as
+// it's computed instead of returning the full slice. A batch with zero
+// matches emits nothing rather than returning ErrItemNotFound.
+func (r *JSONResource) StreamCategoryGroups(ctx context.Context, ids []string, emit func(CategoryGroup) error) error {
+	// See the comment in StreamItemsByIDs: release the lock before the
+	// emit loop below, since emit does I/O and grouping itself only needs
+	// the snapshot.
+	r.mu.RLock()
+	snap := r.snap
+	r.mu.RUnlock()
+
+	// rootCatID → []itemID
+	tmp := make(map[string][]string)
+
+	for i, id := range ids {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return err
+		}
+		if root, ok := snap.itemToRootCategory[id]; ok {
+			tmp[root] = append(tmp[root], id)
+		}
+	}
+
+	for root, itemIDs := range tmp {
+		if err := emit(CategoryGroup{RootCategoryID: root, ItemIDs: itemIDs}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetItem returns a single item by ID, or ErrItemNotFound on a miss.
+func (r *JSONResource) GetItem(ctx context.Context, id string) (Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	itm, ok := r.snap.items[id]
+	if !ok {
+		return Item{}, ErrItemNotFound
+	}
+	return itm, nil
+}
+
+// ListItems returns a page of items matching filter, ordered by ID, along
+// with the total number of items that matched (before pagination). A
+// zero-value Page.Limit falls back to defaultListLimit.
+func (r *JSONResource) ListItems(ctx context.Context, filter ListFilter, page Page) ([]Item, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := r.snap
+
+	candidates := candidateIDs(snap, filter)
+
+	total := len(candidates)
+	limit := page.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset := page.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total {
+		return []Item{}, total, nil
+	}
+
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	window := candidates[offset:end]
+
+	result := make([]Item, 0, len(window))
+	for i, id := range window {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return nil, 0, err
+		}
+		result = append(result, snap.items[id])
+	}
+	return result, total, nil
+}
+
+// candidateIDs returns the sorted item IDs of snap matching filter, without
+// materializing the corresponding items.
+func candidateIDs(snap *snapshot, filter ListFilter) []string {
+	switch {
+	case filter.SellerID != "" && filter.CategoryID != "":
+		byCategory := make(map[string]struct{}, len(snap.itemsByCategory[filter.CategoryID]))
+		for _, id := range snap.itemsByCategory[filter.CategoryID] {
+			byCategory[id] = struct{}{}
+		}
+		matches := make([]string, 0)
+		for _, id := range snap.itemsBySeller[filter.SellerID] {
+			if _, ok := byCategory[id]; ok {
+				matches = append(matches, id)
+			}
+		}
+		return matches
+	case filter.SellerID != "":
+		return snap.itemsBySeller[filter.SellerID]
+	case filter.CategoryID != "":
+		return snap.itemsByCategory[filter.CategoryID]
+	default:
+		all := make([]string, 0, len(snap.items))
+		for id := range snap.items {
+			all = append(all, id)
+		}
+		sort.Strings(all)
+		return all
+	}
+}
+
+// GetItemsByCategory returns every item under categoryID, whether it names
+// a leaf category (matched against item.CategoryID directly) or a root
+// category (matched against the item→root index). Returns
+// ErrCategoryNotFound if categoryID doesn't exist at all.
+func (r *JSONResource) GetItemsByCategory(ctx context.Context, categoryID string) ([]Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := r.snap
+
+	if _, ok := snap.categories[categoryID]; !ok {
+		return nil, ErrCategoryNotFound
+	}
+
+	ids := snap.itemsByCategory[categoryID]
+	if len(ids) == 0 {
+		ids = snap.itemsByRoot[categoryID]
+	}
+
+	result := make([]Item, 0, len(ids))
+	for i, id := range ids {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+		if itm, ok := snap.items[id]; ok {
+			result = append(result, itm)
+		}
+	}
+	return result, nil
+}
+
+// GetCategoryTree returns the category identified by id together with its
+// children recursively resolved from ChildrenCategories. Returns
+// ErrCategoryNotFound if id doesn't exist.
+func (r *JSONResource) GetCategoryTree(ctx context.Context, id string) (CategoryNode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snap := r.snap
+
+	cat, ok := snap.categories[id]
+	if !ok {
+		return CategoryNode{}, ErrCategoryNotFound
+	}
+	return buildCategoryNode(ctx, r, snap, cat, map[string]bool{cat.ID: true})
+}
+
+// buildCategoryNode recursively resolves cat's children into a CategoryNode
+// tree. Unknown child references are skipped rather than failing the whole
+// tree, mirroring the partial-miss tolerance of GetItemsByIDs/GetCategoriesByIDs.
+// visited tracks category IDs already on the current path so a cycle in
+// ChildrenCategories (operator-supplied data, reloaded from disk) is skipped
+// instead of recursing forever.
+func buildCategoryNode(ctx context.Context, r *JSONResource, snap *snapshot, cat Category, visited map[string]bool) (CategoryNode, error) {
+	node := CategoryNode{Category: cat}
+
+	for i, ref := range cat.ChildrenCategories {
+		if err := r.checkCancelled(ctx, i); err != nil {
+			return CategoryNode{}, err
+		}
+		if visited[ref.ID] {
+			continue
+		}
+		child, ok := snap.categories[ref.ID]
+		if !ok {
+			continue
+		}
+		visited[ref.ID] = true
+		childNode, err := buildCategoryNode(ctx, r, snap, child, visited)
+		if err != nil {
+			return CategoryNode{}, err
+		}
+		node.Children = append(node.Children, childNode)
+		delete(visited, ref.ID)
+	}
+
+	return node, nil
+}
@@ -0,0 +1,471 @@
+// Package redisresource implements ports.ItemsResourcePort backed by Redis.
+// Like the sqlite backend, it imports the items/categories JSON files once
+// on first run: items and categories live as hashes (item:{id},
+// category:{id}), seller/category/root memberships live as sets
+// (items_by_seller:{id}, items_by_category:{id}, items_by_root:{id}), and
+// item_to_root is a single hash mapping itemID -> rootCategoryID.
+package redisresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"items/resources/deadline"
+	"items/resources/items"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const defaultListLimit = 50
+
+const (
+	itemKeyPrefix            = "item:"
+	categoryKeyPrefix        = "category:"
+	itemToRootKey            = "item_to_root"
+	itemsBySellerKeyPrefix   = "items_by_seller:"
+	itemsByCategoryKeyPrefix = "items_by_category:"
+	itemsByRootKeyPrefix     = "items_by_root:"
+)
+
+// Resource answers ItemsResourcePort queries against Redis.
+type Resource struct {
+	client           *goredis.Client
+	itemJSONPath     string
+	categoryJSONPath string
+}
+
+// Config configures a Redis-backed Resource.
+type Config struct {
+	Addr             string
+	ItemJSONPath     string
+	CategoryJSONPath string
+}
+
+// New connects to cfg.Addr and imports the JSON files if Redis doesn't
+// already hold the item_to_root index built by a previous run.
+func New(ctx context.Context, cfg Config) (*Resource, error) {
+	client := goredis.NewClient(&goredis.Options{Addr: cfg.Addr})
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("redis: ping %s: %w", cfg.Addr, err)
+	}
+
+	r := &Resource{
+		client:           client,
+		itemJSONPath:     cfg.ItemJSONPath,
+		categoryJSONPath: cfg.CategoryJSONPath,
+	}
+
+	seeded, err := client.Exists(ctx, itemToRootKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: check seed: %w", err)
+	}
+	if seeded == 0 {
+		if err := r.Reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Reload re-imports the items & categories JSON files into Redis, clearing
+// every key this backend owns first so stale entries from a previous
+// dataset don't linger.
+func (r *Resource) Reload() error {
+	ctx := context.Background()
+
+	rawItems, err := items.LoadItemsFile(r.itemJSONPath)
+	if err != nil {
+		return fmt.Errorf("redis: reload: %w", err)
+	}
+	rawCategories, err := items.LoadCategoriesFile(r.categoryJSONPath)
+	if err != nil {
+		return fmt.Errorf("redis: reload: %w", err)
+	}
+
+	if err := r.clearOwnedKeys(ctx); err != nil {
+		return fmt.Errorf("redis: reload: %w", err)
+	}
+
+	pipe := r.client.Pipeline()
+
+	for id, cat := range rawCategories {
+		pathJSON, err := json.Marshal(cat.PathFromRoot)
+		if err != nil {
+			return fmt.Errorf("redis: reload: marshal path_from_root for %s: %w", id, err)
+		}
+		childrenJSON, err := json.Marshal(cat.ChildrenCategories)
+		if err != nil {
+			return fmt.Errorf("redis: reload: marshal children for %s: %w", id, err)
+		}
+		pipe.HSet(ctx, categoryKeyPrefix+id, map[string]any{
+			"name":           cat.Name,
+			"path_from_root": string(pathJSON),
+			"children":       string(childrenJSON),
+		})
+	}
+
+	itemToRoot := make(map[string]any, len(rawItems))
+	for id, itm := range rawItems {
+		pipe.HSet(ctx, itemKeyPrefix+id, map[string]any{
+			"seller_id":    itm.SellerID,
+			"title":        itm.Title,
+			"category_id":  itm.CategoryID,
+			"price":        strconv.FormatFloat(itm.Price, 'f', -1, 64),
+			"date_created": itm.DateCreated,
+			"last_updated": itm.LastUpdated,
+		})
+		pipe.SAdd(ctx, itemsBySellerKeyPrefix+itm.SellerID, id)
+		pipe.SAdd(ctx, itemsByCategoryKeyPrefix+itm.CategoryID, id)
+
+		root := rootCategoryID(itm.CategoryID, rawCategories)
+		itemToRoot[id] = root
+		pipe.SAdd(ctx, itemsByRootKeyPrefix+root, id)
+	}
+	if len(itemToRoot) > 0 {
+		pipe.HSet(ctx, itemToRootKey, itemToRoot)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis: reload: exec pipeline: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Resource) clearOwnedKeys(ctx context.Context) error {
+	patterns := []string{
+		itemKeyPrefix + "*",
+		categoryKeyPrefix + "*",
+		itemsBySellerKeyPrefix + "*",
+		itemsByCategoryKeyPrefix + "*",
+		itemsByRootKeyPrefix + "*",
+	}
+
+	var keys []string
+	for _, pattern := range patterns {
+		found, err := r.client.Keys(ctx, pattern).Result()
+		if err != nil {
+			return fmt.Errorf("list keys %s: %w", pattern, err)
+		}
+		keys = append(keys, found...)
+	}
+	keys = append(keys, itemToRootKey)
+
+	if len(keys) == 0 {
+		return nil
+	}
+	if _, err := r.client.Del(ctx, keys...).Result(); err != nil {
+		return fmt.Errorf("delete keys: %w", err)
+	}
+	return nil
+}
+
+func rootCategoryID(categoryID string, categories map[string]items.Category) string {
+	cat, ok := categories[categoryID]
+	if !ok {
+		return categoryID
+	}
+	if len(cat.PathFromRoot) > 0 {
+		return cat.PathFromRoot[0].ID
+	}
+	return cat.ID
+}
+
+func (r *Resource) GetItem(ctx context.Context, id string) (items.Item, error) {
+	fields, err := r.client.HGetAll(ctx, itemKeyPrefix+id).Result()
+	if err != nil {
+		return items.Item{}, fmt.Errorf("redis: get item %s: %w", id, err)
+	}
+	if len(fields) == 0 {
+		return items.Item{}, items.ErrItemNotFound
+	}
+	return decodeItem(id, fields)
+}
+
+func (r *Resource) GetItemsByIDs(ctx context.Context, ids []string) ([]items.Item, error) {
+	result, err := r.fetchItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+	return result, nil
+}
+
+// StreamItemsByIDs fetches and decodes the whole batch into a []Item with
+// fetchItems before the first emit; it does not resolve ids one at a time.
+func (r *Resource) StreamItemsByIDs(ctx context.Context, ids []string, emit func(items.Item) error) error {
+	result, err := r.fetchItems(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, itm := range result {
+		if err := emit(itm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchItems pipelines an HGetAll per id — Redis's MGET only works on string
+// keys, not hashes — and decodes whichever ones exist, skipping the rest.
+func (r *Resource) fetchItems(ctx context.Context, ids []string) ([]items.Item, error) {
+	result := make([]items.Item, 0, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*goredis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, itemKeyPrefix+id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("redis: fetch items: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		fields, err := cmd.Result()
+		if err != nil || len(fields) == 0 {
+			continue
+		}
+		itm, err := decodeItem(ids[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, itm)
+	}
+	return result, nil
+}
+
+func decodeItem(id string, fields map[string]string) (items.Item, error) {
+	price, err := strconv.ParseFloat(fields["price"], 64)
+	if err != nil {
+		return items.Item{}, fmt.Errorf("redis: parse price for %s: %w", id, err)
+	}
+	return items.Item{
+		ID:          id,
+		SellerID:    fields["seller_id"],
+		Title:       fields["title"],
+		CategoryID:  fields["category_id"],
+		Price:       price,
+		DateCreated: fields["date_created"],
+		LastUpdated: fields["last_updated"],
+	}, nil
+}
+
+func (r *Resource) GetCategoriesByIDs(ctx context.Context, ids []string) ([]items.Category, error) {
+	result, err := r.fetchCategories(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, items.ErrCategoryNotFound
+	}
+	return result, nil
+}
+
+func (r *Resource) fetchCategories(ctx context.Context, ids []string) ([]items.Category, error) {
+	result := make([]items.Category, 0, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*goredis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, categoryKeyPrefix+id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("redis: fetch categories: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		fields, err := cmd.Result()
+		if err != nil || len(fields) == 0 {
+			continue
+		}
+		cat, err := decodeCategory(ids[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cat)
+	}
+	return result, nil
+}
+
+func decodeCategory(id string, fields map[string]string) (items.Category, error) {
+	var cat items.Category
+	cat.ID = id
+	cat.Name = fields["name"]
+	if err := json.Unmarshal([]byte(fields["path_from_root"]), &cat.PathFromRoot); err != nil {
+		return items.Category{}, fmt.Errorf("redis: unmarshal path_from_root for %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(fields["children"]), &cat.ChildrenCategories); err != nil {
+		return items.Category{}, fmt.Errorf("redis: unmarshal children for %s: %w", id, err)
+	}
+	return cat, nil
+}
+
+func (r *Resource) GroupItemIDsByRootCategory(ctx context.Context, ids []string) ([]items.CategoryGroup, error) {
+	if len(ids) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	roots, err := r.client.HMGet(ctx, itemToRootKey, ids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: group items by root category: %w", err)
+	}
+
+	grouped := make(map[string][]string)
+	for i, raw := range roots {
+		root, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		grouped[root] = append(grouped[root], ids[i])
+	}
+	if len(grouped) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	groups := make([]items.CategoryGroup, 0, len(grouped))
+	for root, itemIDs := range grouped {
+		groups = append(groups, items.CategoryGroup{RootCategoryID: root, ItemIDs: itemIDs})
+	}
+	return groups, nil
+}
+
+func (r *Resource) StreamCategoryGroups(ctx context.Context, ids []string, emit func(items.CategoryGroup) error) error {
+	groups, err := r.GroupItemIDsByRootCategory(ctx, ids)
+	if err == items.ErrItemNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := emit(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resource) ListItems(ctx context.Context, filter items.ListFilter, page items.Page) ([]items.Item, int, error) {
+	ids, err := r.candidateIDs(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis: list items: %w", err)
+	}
+	sort.Strings(ids)
+
+	total := len(ids)
+	limit := page.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset := page.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total {
+		return []items.Item{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	result, err := r.fetchItems(ctx, ids[offset:end])
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+// candidateIDs resolves filter to the set of item IDs matching it, falling
+// back to every known item ID (the item_to_root hash's fields) when neither
+// SellerID nor CategoryID is set.
+func (r *Resource) candidateIDs(ctx context.Context, filter items.ListFilter) ([]string, error) {
+	switch {
+	case filter.SellerID != "" && filter.CategoryID != "":
+		return r.client.SInter(ctx, itemsBySellerKeyPrefix+filter.SellerID, itemsByCategoryKeyPrefix+filter.CategoryID).Result()
+	case filter.SellerID != "":
+		return r.client.SMembers(ctx, itemsBySellerKeyPrefix+filter.SellerID).Result()
+	case filter.CategoryID != "":
+		return r.client.SMembers(ctx, itemsByCategoryKeyPrefix+filter.CategoryID).Result()
+	default:
+		return r.client.HKeys(ctx, itemToRootKey).Result()
+	}
+}
+
+func (r *Resource) GetItemsByCategory(ctx context.Context, categoryID string) ([]items.Item, error) {
+	exists, err := r.client.Exists(ctx, categoryKeyPrefix+categoryID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: check category %s: %w", categoryID, err)
+	}
+	if exists == 0 {
+		return nil, items.ErrCategoryNotFound
+	}
+
+	ids, err := r.client.SMembers(ctx, itemsByCategoryKeyPrefix+categoryID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: get items by category %s: %w", categoryID, err)
+	}
+	if len(ids) == 0 {
+		ids, err = r.client.SMembers(ctx, itemsByRootKeyPrefix+categoryID).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis: get items by root category %s: %w", categoryID, err)
+		}
+	}
+
+	return r.fetchItems(ctx, ids)
+}
+
+func (r *Resource) GetCategoryTree(ctx context.Context, id string) (items.CategoryNode, error) {
+	cats, err := r.fetchCategories(ctx, []string{id})
+	if err != nil {
+		return items.CategoryNode{}, err
+	}
+	if len(cats) == 0 {
+		return items.CategoryNode{}, items.ErrCategoryNotFound
+	}
+	return r.buildCategoryNode(ctx, cats[0], map[string]bool{cats[0].ID: true})
+}
+
+// buildCategoryNode recursively resolves cat's children. visited tracks
+// category IDs already on the current path so a cycle in ChildrenCategories
+// is skipped instead of recursing forever.
+func (r *Resource) buildCategoryNode(ctx context.Context, cat items.Category, visited map[string]bool) (items.CategoryNode, error) {
+	node := items.CategoryNode{Category: cat}
+	for i, ref := range cat.ChildrenCategories {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return items.CategoryNode{}, err
+		}
+		if visited[ref.ID] {
+			continue
+		}
+
+		children, err := r.fetchCategories(ctx, []string{ref.ID})
+		if err != nil {
+			return items.CategoryNode{}, err
+		}
+		if len(children) == 0 {
+			continue
+		}
+
+		visited[ref.ID] = true
+		childNode, err := r.buildCategoryNode(ctx, children[0], visited)
+		if err != nil {
+			return items.CategoryNode{}, err
+		}
+		node.Children = append(node.Children, childNode)
+		delete(visited, ref.ID)
+	}
+	return node, nil
+}
@@ -0,0 +1,502 @@
+// Package sqlite implements ports.ItemsResourcePort backed by a local
+// SQLite database. The database is imported once, on first run, from the
+// same items/categories JSON files the json backend reads directly.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"items/resources/deadline"
+	"items/resources/items"
+
+	_ "modernc.org/sqlite"
+)
+
+const defaultListLimit = 50
+
+// Resource answers ItemsResourcePort queries against a SQLite database,
+// using indexes on category_id/seller_id and prepared `WHERE id IN (...)`
+// statements instead of the json backend's in-memory maps.
+type Resource struct {
+	db               *sql.DB
+	itemJSONPath     string
+	categoryJSONPath string
+}
+
+// Config configures a SQLite-backed Resource.
+type Config struct {
+	DBPath           string
+	ItemJSONPath     string
+	CategoryJSONPath string
+}
+
+// New opens (creating if needed) the SQLite database at cfg.DBPath,
+// migrates its schema, and imports the JSON files into it if it's empty.
+func New(ctx context.Context, cfg Config) (*Resource, error) {
+	db, err := sql.Open("sqlite", cfg.DBPath)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: open %s: %w", cfg.DBPath, err)
+	}
+
+	r := &Resource{
+		db:               db,
+		itemJSONPath:     cfg.ItemJSONPath,
+		categoryJSONPath: cfg.CategoryJSONPath,
+	}
+
+	if err := r.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	empty, err := r.isEmpty(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if empty {
+		if err := r.Reload(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Resource) migrate(ctx context.Context) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS categories (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			path_from_root TEXT NOT NULL,
+			children TEXT NOT NULL,
+			root_category_id TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS items (
+			id TEXT PRIMARY KEY,
+			seller_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			category_id TEXT NOT NULL,
+			price REAL NOT NULL,
+			date_created TEXT NOT NULL,
+			last_updated TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_items_category ON items(category_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_items_seller ON items(seller_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_categories_root ON categories(root_category_id)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("sqlite: migrate: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Resource) isEmpty(ctx context.Context) (bool, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM items`).Scan(&count); err != nil {
+		return false, fmt.Errorf("sqlite: count items: %w", err)
+	}
+	return count == 0, nil
+}
+
+// Reload re-imports the items & categories JSON files into the database
+// inside a transaction, so a malformed file leaves the previous data
+// intact instead of leaving the database half-written.
+func (r *Resource) Reload() error {
+	ctx := context.Background()
+
+	rawItems, err := items.LoadItemsFile(r.itemJSONPath)
+	if err != nil {
+		return fmt.Errorf("sqlite: reload: %w", err)
+	}
+	rawCategories, err := items.LoadCategoriesFile(r.categoryJSONPath)
+	if err != nil {
+		return fmt.Errorf("sqlite: reload: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite: reload: begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM items`); err != nil {
+		return fmt.Errorf("sqlite: reload: clear items: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM categories`); err != nil {
+		return fmt.Errorf("sqlite: reload: clear categories: %w", err)
+	}
+
+	catStmt, err := tx.PrepareContext(ctx, `INSERT INTO categories (id, name, path_from_root, children, root_category_id) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("sqlite: reload: prepare categories insert: %w", err)
+	}
+	defer catStmt.Close()
+
+	for id, cat := range rawCategories {
+		pathJSON, err := json.Marshal(cat.PathFromRoot)
+		if err != nil {
+			return fmt.Errorf("sqlite: reload: marshal path_from_root for %s: %w", id, err)
+		}
+		childrenJSON, err := json.Marshal(cat.ChildrenCategories)
+		if err != nil {
+			return fmt.Errorf("sqlite: reload: marshal children for %s: %w", id, err)
+		}
+		rootID := cat.ID
+		if len(cat.PathFromRoot) > 0 {
+			rootID = cat.PathFromRoot[0].ID
+		}
+		if _, err := catStmt.ExecContext(ctx, id, cat.Name, string(pathJSON), string(childrenJSON), rootID); err != nil {
+			return fmt.Errorf("sqlite: reload: insert category %s: %w", id, err)
+		}
+	}
+
+	itemStmt, err := tx.PrepareContext(ctx, `INSERT INTO items (id, seller_id, title, category_id, price, date_created, last_updated) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("sqlite: reload: prepare items insert: %w", err)
+	}
+	defer itemStmt.Close()
+
+	for id, itm := range rawItems {
+		if _, err := itemStmt.ExecContext(ctx, id, itm.SellerID, itm.Title, itm.CategoryID, itm.Price, itm.DateCreated, itm.LastUpdated); err != nil {
+			return fmt.Errorf("sqlite: reload: insert item %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite: reload: commit: %w", err)
+	}
+
+	return nil
+}
+
+const itemColumns = "id, seller_id, title, category_id, price, date_created, last_updated"
+
+func (r *Resource) GetItem(ctx context.Context, id string) (items.Item, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+itemColumns+` FROM items WHERE id = ?`, id)
+
+	var itm items.Item
+	err := row.Scan(&itm.ID, &itm.SellerID, &itm.Title, &itm.CategoryID, &itm.Price, &itm.DateCreated, &itm.LastUpdated)
+	if err == sql.ErrNoRows {
+		return items.Item{}, items.ErrItemNotFound
+	}
+	if err != nil {
+		return items.Item{}, fmt.Errorf("sqlite: get item %s: %w", id, err)
+	}
+	return itm, nil
+}
+
+func (r *Resource) GetItemsByIDs(ctx context.Context, ids []string) ([]items.Item, error) {
+	if len(ids) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	placeholders, args := inClause(ids)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+itemColumns+` FROM items WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: get items by ids: %w", err)
+	}
+	defer rows.Close()
+
+	result, err := scanItems(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+	return result, nil
+}
+
+func (r *Resource) StreamItemsByIDs(ctx context.Context, ids []string, emit func(items.Item) error) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders, args := inClause(ids)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+itemColumns+` FROM items WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("sqlite: stream items by ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itm items.Item
+		if err := rows.Scan(&itm.ID, &itm.SellerID, &itm.Title, &itm.CategoryID, &itm.Price, &itm.DateCreated, &itm.LastUpdated); err != nil {
+			return fmt.Errorf("sqlite: scan item: %w", err)
+		}
+		if err := emit(itm); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *Resource) GetCategoriesByIDs(ctx context.Context, ids []string) ([]items.Category, error) {
+	if len(ids) == 0 {
+		return nil, items.ErrCategoryNotFound
+	}
+
+	placeholders, args := inClause(ids)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, path_from_root, children FROM categories WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: get categories by ids: %w", err)
+	}
+	defer rows.Close()
+
+	var result []items.Category
+	for rows.Next() {
+		cat, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, items.ErrCategoryNotFound
+	}
+	return result, nil
+}
+
+func (r *Resource) GroupItemIDsByRootCategory(ctx context.Context, ids []string) ([]items.CategoryGroup, error) {
+	if len(ids) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	placeholders, args := inClause(ids)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, c.root_category_id
+		FROM items i
+		JOIN categories c ON i.category_id = c.id
+		WHERE i.id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: group items by root category: %w", err)
+	}
+	defer rows.Close()
+
+	grouped := make(map[string][]string)
+	for rows.Next() {
+		var id, root string
+		if err := rows.Scan(&id, &root); err != nil {
+			return nil, fmt.Errorf("sqlite: scan group row: %w", err)
+		}
+		grouped[root] = append(grouped[root], id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(grouped) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	groups := make([]items.CategoryGroup, 0, len(grouped))
+	for root, itemIDs := range grouped {
+		groups = append(groups, items.CategoryGroup{RootCategoryID: root, ItemIDs: itemIDs})
+	}
+	return groups, nil
+}
+
+// StreamCategoryGroups groups still need every matching row before the first
+// group can be emitted, so it just delegates to GroupItemIDsByRootCategory
+// and streams the already-computed groups.
+func (r *Resource) StreamCategoryGroups(ctx context.Context, ids []string, emit func(items.CategoryGroup) error) error {
+	groups, err := r.GroupItemIDsByRootCategory(ctx, ids)
+	if err == items.ErrItemNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := emit(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resource) ListItems(ctx context.Context, filter items.ListFilter, page items.Page) ([]items.Item, int, error) {
+	where, args := listFilterClause(filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM items`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("sqlite: count list items: %w", err)
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset := page.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `SELECT ` + itemColumns + ` FROM items` + where + ` ORDER BY id LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, append(append([]any{}, args...), limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("sqlite: list items: %w", err)
+	}
+	defer rows.Close()
+
+	result, err := scanItems(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *Resource) GetItemsByCategory(ctx context.Context, categoryID string) ([]items.Item, error) {
+	var exists int
+	err := r.db.QueryRowContext(ctx, `SELECT 1 FROM categories WHERE id = ?`, categoryID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, items.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: check category %s: %w", categoryID, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, i.seller_id, i.title, i.category_id, i.price, i.date_created, i.last_updated
+		FROM items i
+		JOIN categories c ON i.category_id = c.id
+		WHERE c.id = ? OR c.root_category_id = ?
+		ORDER BY i.id`, categoryID, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: get items by category %s: %w", categoryID, err)
+	}
+	defer rows.Close()
+
+	return scanItems(rows)
+}
+
+func (r *Resource) GetCategoryTree(ctx context.Context, id string) (items.CategoryNode, error) {
+	cat, err := r.getCategory(ctx, id)
+	if err != nil {
+		return items.CategoryNode{}, err
+	}
+	return r.buildCategoryNode(ctx, cat, map[string]bool{cat.ID: true})
+}
+
+func (r *Resource) getCategory(ctx context.Context, id string) (items.Category, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT id, name, path_from_root, children FROM categories WHERE id = ?`, id)
+
+	var (
+		cat          items.Category
+		pathJSON     string
+		childrenJSON string
+	)
+	err := row.Scan(&cat.ID, &cat.Name, &pathJSON, &childrenJSON)
+	if err == sql.ErrNoRows {
+		return items.Category{}, items.ErrCategoryNotFound
+	}
+	if err != nil {
+		return items.Category{}, fmt.Errorf("sqlite: get category %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(pathJSON), &cat.PathFromRoot); err != nil {
+		return items.Category{}, fmt.Errorf("sqlite: unmarshal path_from_root for %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(childrenJSON), &cat.ChildrenCategories); err != nil {
+		return items.Category{}, fmt.Errorf("sqlite: unmarshal children for %s: %w", id, err)
+	}
+	return cat, nil
+}
+
+// buildCategoryNode recursively resolves cat's children. visited tracks
+// category IDs already on the current path so a cycle in ChildrenCategories
+// is skipped instead of recursing forever.
+func (r *Resource) buildCategoryNode(ctx context.Context, cat items.Category, visited map[string]bool) (items.CategoryNode, error) {
+	node := items.CategoryNode{Category: cat}
+	for i, ref := range cat.ChildrenCategories {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return items.CategoryNode{}, err
+		}
+		if visited[ref.ID] {
+			continue
+		}
+
+		child, err := r.getCategory(ctx, ref.ID)
+		if err == items.ErrCategoryNotFound {
+			continue
+		}
+		if err != nil {
+			return items.CategoryNode{}, err
+		}
+
+		visited[ref.ID] = true
+		childNode, err := r.buildCategoryNode(ctx, child, visited)
+		if err != nil {
+			return items.CategoryNode{}, err
+		}
+		node.Children = append(node.Children, childNode)
+		delete(visited, ref.ID)
+	}
+	return node, nil
+}
+
+func scanItems(rows *sql.Rows) ([]items.Item, error) {
+	result := make([]items.Item, 0)
+	for rows.Next() {
+		var itm items.Item
+		if err := rows.Scan(&itm.ID, &itm.SellerID, &itm.Title, &itm.CategoryID, &itm.Price, &itm.DateCreated, &itm.LastUpdated); err != nil {
+			return nil, fmt.Errorf("sqlite: scan item: %w", err)
+		}
+		result = append(result, itm)
+	}
+	return result, rows.Err()
+}
+
+func scanCategory(rows *sql.Rows) (items.Category, error) {
+	var (
+		cat          items.Category
+		pathJSON     string
+		childrenJSON string
+	)
+	if err := rows.Scan(&cat.ID, &cat.Name, &pathJSON, &childrenJSON); err != nil {
+		return items.Category{}, fmt.Errorf("sqlite: scan category: %w", err)
+	}
+	if err := json.Unmarshal([]byte(pathJSON), &cat.PathFromRoot); err != nil {
+		return items.Category{}, fmt.Errorf("sqlite: unmarshal path_from_root: %w", err)
+	}
+	if err := json.Unmarshal([]byte(childrenJSON), &cat.ChildrenCategories); err != nil {
+		return items.Category{}, fmt.Errorf("sqlite: unmarshal children: %w", err)
+	}
+	return cat, nil
+}
+
+func inClause(ids []string) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ","), args
+}
+
+func listFilterClause(filter items.ListFilter) (string, []any) {
+	var conds []string
+	var args []any
+	if filter.SellerID != "" {
+		conds = append(conds, "seller_id = ?")
+		args = append(args, filter.SellerID)
+	}
+	if filter.CategoryID != "" {
+		conds = append(conds, "category_id = ?")
+		args = append(args, filter.CategoryID)
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,414 @@
+// Package upstream implements ports.ItemsResourcePort by proxying to the
+// real MercadoLibre API, with an in-memory LRU cache and singleflight
+// request coalescing so concurrent callers asking for the same ID only hit
+// the network once.
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"items/resources/deadline"
+	"items/resources/items"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultBaseURL           = "https://api.mercadolibre.com"
+	defaultSiteID            = "MLA"
+	defaultCacheLen          = 10000
+	defaultListLimit         = 50
+	maxCategoryItemsPageSize = 200
+)
+
+// Resource proxies item/category look‑ups to the real MercadoLibre API.
+type Resource struct {
+	baseURL    string
+	siteID     string
+	httpClient *http.Client
+	itemCache  *lru.Cache[string, items.Item]
+	catCache   *lru.Cache[string, items.Category]
+	group      singleflight.Group
+}
+
+// Config configures an upstream-backed Resource.
+type Config struct {
+	BaseURL    string
+	SiteID     string
+	CacheLen   int
+	HTTPClient *http.Client
+}
+
+// New builds a Resource proxying to cfg.BaseURL (the real MercadoLibre API
+// if unset).
+func New(cfg Config) (*Resource, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	siteID := cfg.SiteID
+	if siteID == "" {
+		siteID = defaultSiteID
+	}
+	cacheLen := cfg.CacheLen
+	if cacheLen <= 0 {
+		cacheLen = defaultCacheLen
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	itemCache, err := lru.New[string, items.Item](cacheLen)
+	if err != nil {
+		return nil, fmt.Errorf("upstream: new item cache: %w", err)
+	}
+	catCache, err := lru.New[string, items.Category](cacheLen)
+	if err != nil {
+		return nil, fmt.Errorf("upstream: new category cache: %w", err)
+	}
+
+	return &Resource{
+		baseURL:    baseURL,
+		siteID:     siteID,
+		httpClient: httpClient,
+		itemCache:  itemCache,
+		catCache:   catCache,
+	}, nil
+}
+
+func (r *Resource) GetItem(ctx context.Context, id string) (items.Item, error) {
+	if itm, ok := r.itemCache.Get(id); ok {
+		return itm, nil
+	}
+
+	v, err, _ := r.group.Do("item:"+id, func() (interface{}, error) {
+		return r.fetchItem(ctx, id)
+	})
+	if err != nil {
+		return items.Item{}, err
+	}
+
+	itm := v.(items.Item)
+	r.itemCache.Add(id, itm)
+	return itm, nil
+}
+
+func (r *Resource) fetchItem(ctx context.Context, id string) (items.Item, error) {
+	var itm items.Item
+	if err := r.getJSON(ctx, "/items/"+id, items.ErrItemNotFound, &itm); err != nil {
+		return items.Item{}, err
+	}
+	itm.ID = id
+	return itm, nil
+}
+
+// GetCategory returns a single category by ID, caching it the same way
+// GetItem caches items. It's not part of ports.ItemsResourcePort, but the
+// other methods below and the json/sqlite/redis backends' analogous helpers
+// all need single-category look‑ups internally.
+func (r *Resource) GetCategory(ctx context.Context, id string) (items.Category, error) {
+	if cat, ok := r.catCache.Get(id); ok {
+		return cat, nil
+	}
+
+	v, err, _ := r.group.Do("category:"+id, func() (interface{}, error) {
+		return r.fetchCategory(ctx, id)
+	})
+	if err != nil {
+		return items.Category{}, err
+	}
+
+	cat := v.(items.Category)
+	r.catCache.Add(id, cat)
+	return cat, nil
+}
+
+func (r *Resource) fetchCategory(ctx context.Context, id string) (items.Category, error) {
+	var cat items.Category
+	if err := r.getJSON(ctx, "/categories/"+id, items.ErrCategoryNotFound, &cat); err != nil {
+		return items.Category{}, err
+	}
+	cat.ID = id
+	return cat, nil
+}
+
+func (r *Resource) getJSON(ctx context.Context, path string, notFoundErr error, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("upstream: build request for %s: %w", path, err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upstream: request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return notFoundErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("upstream: %s returned %d: %s", path, resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("upstream: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *Resource) GetItemsByIDs(ctx context.Context, ids []string) ([]items.Item, error) {
+	result := make([]items.Item, 0, len(ids))
+	for i, id := range ids {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+		itm, err := r.GetItem(ctx, id)
+		if err == items.ErrItemNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, itm)
+	}
+	if len(result) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+	return result, nil
+}
+
+func (r *Resource) StreamItemsByIDs(ctx context.Context, ids []string, emit func(items.Item) error) error {
+	for i, id := range ids {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return err
+		}
+		itm, err := r.GetItem(ctx, id)
+		if err == items.ErrItemNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := emit(itm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resource) GetCategoriesByIDs(ctx context.Context, ids []string) ([]items.Category, error) {
+	result := make([]items.Category, 0, len(ids))
+	for i, id := range ids {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+		cat, err := r.GetCategory(ctx, id)
+		if err == items.ErrCategoryNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cat)
+	}
+	if len(result) == 0 {
+		return nil, items.ErrCategoryNotFound
+	}
+	return result, nil
+}
+
+func (r *Resource) GroupItemIDsByRootCategory(ctx context.Context, ids []string) ([]items.CategoryGroup, error) {
+	if len(ids) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	grouped := make(map[string][]string)
+	for i, id := range ids {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return nil, err
+		}
+
+		itm, err := r.GetItem(ctx, id)
+		if err == items.ErrItemNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		root, err := r.rootCategoryID(ctx, itm.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		grouped[root] = append(grouped[root], id)
+	}
+	if len(grouped) == 0 {
+		return nil, items.ErrItemNotFound
+	}
+
+	groups := make([]items.CategoryGroup, 0, len(grouped))
+	for root, itemIDs := range grouped {
+		groups = append(groups, items.CategoryGroup{RootCategoryID: root, ItemIDs: itemIDs})
+	}
+	return groups, nil
+}
+
+func (r *Resource) rootCategoryID(ctx context.Context, categoryID string) (string, error) {
+	cat, err := r.GetCategory(ctx, categoryID)
+	if err != nil {
+		return "", err
+	}
+	if len(cat.PathFromRoot) > 0 {
+		return cat.PathFromRoot[0].ID, nil
+	}
+	return cat.ID, nil
+}
+
+func (r *Resource) StreamCategoryGroups(ctx context.Context, ids []string, emit func(items.CategoryGroup) error) error {
+	groups, err := r.GroupItemIDsByRootCategory(ctx, ids)
+	if err == items.ErrItemNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := emit(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchResult matches the shape shared by the seller-scoped
+// /users/{id}/items/search and category-scoped /sites/{site}/search
+// endpoints: a page of result objects (only the id field is used here) plus
+// a paging.total count.
+type searchResult struct {
+	Results []struct {
+		ID string `json:"id"`
+	} `json:"results"`
+	Paging struct {
+		Total int `json:"total"`
+	} `json:"paging"`
+}
+
+// ListItems proxies to the seller- or category-scoped search endpoints.
+// MercadoLibre doesn't expose a way to list every item, so at least one of
+// filter.SellerID/filter.CategoryID must be set. When both are set, only
+// SellerID is sent upstream; CategoryID is ignored.
+func (r *Resource) ListItems(ctx context.Context, filter items.ListFilter, page items.Page) ([]items.Item, int, error) {
+	limit := page.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset := page.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	var path string
+	switch {
+	case filter.SellerID != "":
+		path = fmt.Sprintf("/users/%s/items/search?offset=%d&limit=%d", filter.SellerID, offset, limit)
+	case filter.CategoryID != "":
+		path = fmt.Sprintf("/sites/%s/search?category=%s&offset=%d&limit=%d", r.siteID, filter.CategoryID, offset, limit)
+	default:
+		return nil, 0, fmt.Errorf("upstream: ListItems requires seller_id or category_id")
+	}
+
+	var search searchResult
+	if err := r.getJSON(ctx, path, items.ErrItemNotFound, &search); err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]items.Item, 0, len(search.Results))
+	for i, res := range search.Results {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return nil, 0, err
+		}
+		itm, err := r.GetItem(ctx, res.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, itm)
+	}
+	return result, search.Paging.Total, nil
+}
+
+// GetItemsByCategory fetches a single bounded page of the category's items
+// from the upstream search endpoint; unlike the json/sqlite/redis backends
+// it can't cheaply enumerate every item under a category, so results beyond
+// maxCategoryItemsPageSize are dropped (and logged) rather than fetched.
+func (r *Resource) GetItemsByCategory(ctx context.Context, categoryID string) ([]items.Item, error) {
+	if _, err := r.GetCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	result, total, err := r.ListItems(ctx, items.ListFilter{CategoryID: categoryID}, items.Page{Limit: maxCategoryItemsPageSize})
+	if err != nil {
+		return nil, err
+	}
+	if total > len(result) {
+		log.Printf("upstream: category %s has %d items, returning only the first %d (GetItemsByCategory doesn't paginate)", categoryID, total, len(result))
+	}
+	return result, nil
+}
+
+func (r *Resource) GetCategoryTree(ctx context.Context, id string) (items.CategoryNode, error) {
+	cat, err := r.GetCategory(ctx, id)
+	if err != nil {
+		return items.CategoryNode{}, err
+	}
+	return r.buildCategoryNode(ctx, cat, map[string]bool{cat.ID: true})
+}
+
+// buildCategoryNode recursively resolves cat's children. visited tracks
+// category IDs already on the current path so a cycle in ChildrenCategories
+// is skipped instead of recursing forever.
+func (r *Resource) buildCategoryNode(ctx context.Context, cat items.Category, visited map[string]bool) (items.CategoryNode, error) {
+	node := items.CategoryNode{Category: cat}
+	for i, ref := range cat.ChildrenCategories {
+		if err := deadline.CheckCancelled(ctx, i); err != nil {
+			return items.CategoryNode{}, err
+		}
+		if visited[ref.ID] {
+			continue
+		}
+
+		child, err := r.GetCategory(ctx, ref.ID)
+		if err == items.ErrCategoryNotFound {
+			continue
+		}
+		if err != nil {
+			return items.CategoryNode{}, err
+		}
+
+		visited[ref.ID] = true
+		childNode, err := r.buildCategoryNode(ctx, child, visited)
+		if err != nil {
+			return items.CategoryNode{}, err
+		}
+		node.Children = append(node.Children, childNode)
+		delete(visited, ref.ID)
+	}
+	return node, nil
+}
+
+// Reload drops the in-memory caches so subsequent look‑ups re-fetch from the
+// upstream API instead of serving stale cached responses. There's no local
+// copy to re-import from, unlike the json/sqlite/redis backends.
+func (r *Resource) Reload() error {
+	r.itemCache.Purge()
+	r.catCache.Purge()
+	return nil
+}